@@ -3,6 +3,7 @@ package api
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/konveyor/tackle2-hub/auth"
+	"github.com/konveyor/tackle2-hub/identity"
 	"github.com/konveyor/tackle2-hub/model"
 	"net/http"
 )
@@ -12,6 +13,7 @@ import (
 const (
 	IdentitiesRoot    = "/identities"
 	IdentityRoot      = IdentitiesRoot + "/:" + ID
+	IdentitySecret    = IdentityRoot + "/secret"
 	AppIdentitiesRoot = ApplicationRoot + IdentitiesRoot
 )
 
@@ -32,6 +34,9 @@ func (h IdentityHandler) AddRoutes(e *gin.Engine) {
 	routeGroup.DELETE(IdentityRoot, h.Delete)
 	routeGroup.GET(AppIdentitiesRoot, h.ListByApplication)
 	routeGroup.GET(AppIdentitiesRoot+"/", h.ListByApplication)
+	secretGroup := e.Group("/")
+	secretGroup.Use(auth.AuthorizationRequired(h.AuthProvider, "identities.secret"))
+	secretGroup.GET(IdentitySecret, h.GetSecret)
 }
 
 // Get godoc
@@ -61,11 +66,25 @@ func (h IdentityHandler) Get(ctx *gin.Context) {
 // @description List all identities.
 // @tags get
 // @produce json
-// @success 200 {object} []Identity
+// @success 200 {object} api.PaginationResult[Identity]
 // @router /identities [get]
+// @param q query string false "Substring match on name/description"
+// @param kind query []string false "Filter by kind, multi-valued"
+// @param page query int false "Page number, 1-based"
+// @param pageSize query int false "Items per page"
+// @param sort query string false "Comma-separated sort columns, prefix with - for descending"
 func (h IdentityHandler) List(ctx *gin.Context) {
+	db := h.filter(ctx, h.DB, []string{"name", "description"}, "kind")
+	var total int64
+	result := db.Model(&model.Identity{}).Count(&total)
+	if result.Error != nil {
+		h.listFailed(ctx, result.Error)
+		return
+	}
+	db = h.sorter(ctx, db, map[string]string{"name": "name", "kind": "kind", "createTime": "createtime"})
+	db, page, pageSize := h.pager(ctx, db)
 	var list []model.Identity
-	result := h.DB.Find(&list)
+	result = db.Find(&list)
 	if result.Error != nil {
 		h.listFailed(ctx, result.Error)
 		return
@@ -77,7 +96,12 @@ func (h IdentityHandler) List(ctx *gin.Context) {
 		resources = append(resources, r)
 	}
 
-	ctx.JSON(http.StatusOK, resources)
+	ctx.JSON(http.StatusOK, PaginationResult[Identity]{
+		Items:    resources,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	})
 }
 
 // Create godoc
@@ -96,13 +120,28 @@ func (h IdentityHandler) Create(ctx *gin.Context) {
 		h.bindFailed(ctx, err)
 		return
 	}
+	provider, err := identity.Get(r.Backend)
+	if err != nil {
+		h.createFailed(ctx, err)
+		return
+	}
 	m := r.Model()
+	if r.Backend != identity.DB {
+		// Non-DB backends keep the secret material out of the hub's own
+		// DB entirely; it lives only with the provider.
+		m.User, m.Password, m.Key = "", "", ""
+	}
 	ref := &model.Identity{}
 	err = m.Encrypt(ref)
 	if err != nil {
 		h.updateFailed(ctx, err)
 		return
 	}
+	err = provider.Put(h.DB, m.ID, m.Settings, identity.Secret{User: r.User, Password: r.Password, Key: r.Key})
+	if err != nil {
+		h.createFailed(ctx, err)
+		return
+	}
 	result := h.DB.Create(m)
 	if result.Error != nil {
 		h.createFailed(ctx, result.Error)
@@ -122,13 +161,23 @@ func (h IdentityHandler) Create(ctx *gin.Context) {
 // @param id path string true "Identity ID"
 func (h IdentityHandler) Delete(ctx *gin.Context) {
 	id := h.pk(ctx)
-	identity := &model.Identity{}
-	result := h.DB.First(identity, id)
+	m := &model.Identity{}
+	result := h.DB.First(m, id)
 	if result.Error != nil {
 		h.deleteFailed(ctx, result.Error)
 		return
 	}
-	result = h.DB.Delete(identity)
+	provider, err := identity.Get(m.Backend)
+	if err != nil {
+		h.deleteFailed(ctx, err)
+		return
+	}
+	err = provider.Delete(h.DB, m.ID, m.Settings)
+	if err != nil {
+		h.deleteFailed(ctx, err)
+		return
+	}
+	result = h.DB.Delete(m)
 	if result.Error != nil {
 		h.deleteFailed(ctx, result.Error)
 		return
@@ -154,6 +203,11 @@ func (h IdentityHandler) Update(ctx *gin.Context) {
 		h.bindFailed(ctx, err)
 		return
 	}
+	provider, err := identity.Get(r.Backend)
+	if err != nil {
+		h.updateFailed(ctx, err)
+		return
+	}
 	ref := &model.Identity{}
 	err = h.DB.First(ref, id).Error
 	if err != nil {
@@ -161,74 +215,164 @@ func (h IdentityHandler) Update(ctx *gin.Context) {
 		return
 	}
 	m := r.Model()
+	if r.Backend != identity.DB {
+		// Non-DB backends keep the secret material out of the hub's own
+		// DB entirely; it lives only with the provider.
+		m.User, m.Password, m.Key = "", "", ""
+	}
 	err = m.Encrypt(ref)
 	if err != nil {
 		h.updateFailed(ctx, err)
 		return
 	}
 	m.ID = id
+	err = provider.Put(h.DB, m.ID, m.Settings, identity.Secret{User: r.User, Password: r.Password, Key: r.Key})
+	if err != nil {
+		h.updateFailed(ctx, err)
+		return
+	}
 	db := h.DB.Model(m)
 	err = db.Updates(h.fields(m)).Error
 	if err != nil {
 		h.updateFailed(ctx, err)
 		return
 	}
+	if ref.Backend != r.Backend {
+		// The identity just migrated to a different backend; the old one
+		// still holds a live copy of the secret and would otherwise be
+		// orphaned.
+		oldProvider, err := identity.Get(ref.Backend)
+		if err == nil {
+			_ = oldProvider.Delete(h.DB, ref.ID, ref.Settings)
+		}
+	}
 
 	ctx.Status(http.StatusNoContent)
 }
 
+// GetSecret godoc
+// @summary Get the decrypted secret material for an identity.
+// @description Get the decrypted secret material for an identity. Gated by
+// the stricter "identities.secret" scope so that only addons/tasks fetch
+// credentials, and only on demand.
+// @tags get
+// @produce json
+// @success 200 {object} IdentitySecret
+// @router /identities/{id}/secret [get]
+// @param id path string true "Identity ID"
+func (h IdentityHandler) GetSecret(ctx *gin.Context) {
+	id := h.pk(ctx)
+	m := &model.Identity{}
+	result := h.DB.First(m, id)
+	if result.Error != nil {
+		h.getFailed(ctx, result.Error)
+		return
+	}
+	provider, err := identity.Get(m.Backend)
+	if err != nil {
+		h.getFailed(ctx, err)
+		return
+	}
+	secret, err := provider.Get(h.DB, m.ID, m.Settings)
+	if err != nil {
+		h.getFailed(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, IdentitySecret{
+		User:     secret.User,
+		Password: secret.Password,
+		Key:      secret.Key,
+	})
+}
+
 // ListByApplication  godoc
 // @summary List identities for an application.
 // @description List identities for an application.
 // @tags get
 // @produce json
-// @success 200 {object} []Identity
+// @success 200 {object} api.PaginationResult[Identity]
 // @router /application-inventory/application/{id}/identities [get]
 // @param id path int true "Application ID"
+// @param q query string false "Substring match on name/description"
+// @param kind query []string false "Filter by kind, multi-valued"
+// @param page query int false "Page number, 1-based"
+// @param pageSize query int false "Items per page"
+// @param sort query string false "Comma-separated sort columns, prefix with - for descending"
 func (h IdentityHandler) ListByApplication(ctx *gin.Context) {
 	id := h.pk(ctx)
-	m := &model.Application{}
-	db := h.preLoad(h.DB, "Identities")
-	result := db.First(m, id)
+	app := &model.Application{}
+	result := h.DB.First(app, id)
 	if result.Error != nil {
 		h.getFailed(ctx, result.Error)
 		return
 	}
+	// Where/Order/Offset/Limit chained onto db apply to the association's
+	// target table (model.Identity), not model.Application, so this reuses
+	// the exact same filter/sorter/pager helpers every other list handler
+	// does instead of re-implementing matching/sorting/paging in memory.
+	db := h.filter(ctx, h.DB.Model(app), []string{"name", "description"}, "kind")
+	assoc := db.Association("Identities")
+	total := assoc.Count()
+	if assoc.Error != nil {
+		h.listFailed(ctx, assoc.Error)
+		return
+	}
+	db = h.sorter(ctx, db, map[string]string{"name": "name", "kind": "kind"})
+	db, page, pageSize := h.pager(ctx, db)
+	var list []model.Identity
+	err := db.Association("Identities").Find(&list)
+	if err != nil {
+		h.listFailed(ctx, err)
+		return
+	}
 	resources := []Identity{}
-	for i := range m.Identities {
-		id := Identity{}
-		id.With(&m.Identities[i])
-		resources = append(
-			resources,
-			id)
+	for i := range list {
+		r := Identity{}
+		r.With(&list[i])
+		resources = append(resources, r)
 	}
 
-	ctx.JSON(http.StatusOK, resources)
+	ctx.JSON(http.StatusOK, PaginationResult[Identity]{
+		Items:    resources,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	})
 }
 
 //
-// Identity REST resource.
+// Identity REST resource. User/Password/Key are write-only: they are
+// accepted on Create/Update but never populated back out on Get/List, so
+// callers fetch credentials via GetSecret instead.
 type Identity struct {
 	Resource
 	Kind        string `json:"kind" binding:"required"`
 	Name        string `json:"name" binding:"required"`
 	Description string `json:"description"`
-	User        string `json:"user"`
-	Password    string `json:"password"`
-	Key         string `json:"key"`
+	Backend     string `json:"backend" binding:"required,oneof=db vault k8s"`
 	Settings    string `json:"settings"`
+	User        string `json:"user,omitempty"`
+	Password    string `json:"password,omitempty"`
+	Key         string `json:"key,omitempty"`
 }
 
 //
-// With updates the resource with the model.
+// IdentitySecret REST resource returned by GetSecret.
+type IdentitySecret struct {
+	User     string `json:"user,omitempty"`
+	Password string `json:"password,omitempty"`
+	Key      string `json:"key,omitempty"`
+}
+
+//
+// With updates the resource with the model. Secret material is
+// intentionally omitted; see GetSecret.
 func (r *Identity) With(m *model.Identity) {
 	r.Resource.With(&m.Model)
 	r.Kind = m.Kind
 	r.Name = m.Name
 	r.Description = m.Description
-	r.User = m.User
-	r.Password = m.Password
-	r.Key = m.Key
+	r.Backend = m.Backend
 	r.Settings = m.Settings
 }
 
@@ -239,10 +383,11 @@ func (r *Identity) Model() (m *model.Identity) {
 		Kind:        r.Kind,
 		Name:        r.Name,
 		Description: r.Description,
+		Backend:     r.Backend,
+		Settings:    r.Settings,
 		User:        r.User,
 		Password:    r.Password,
 		Key:         r.Key,
-		Settings:    r.Settings,
 	}
 	m.ID = r.ID
 
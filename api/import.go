@@ -2,9 +2,9 @@ package api
 
 import (
 	"bytes"
-	"encoding/csv"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/konveyor/tackle2-hub/importer"
 	"github.com/konveyor/tackle2-hub/model"
 	"io"
 	"net/http"
@@ -12,10 +12,9 @@ import (
 )
 
 //
-// Record types
+// SSE tuning.
 const (
-	RecordTypeApplication = "1"
-	RecordTypeDependency  = "2"
+	eventsHeartbeat = 15 * time.Second
 )
 
 //
@@ -23,6 +22,7 @@ const (
 const (
 	InProgress = "In Progress"
 	Completed  = "Completed"
+	Canceled   = "Canceled"
 )
 
 //
@@ -34,6 +34,9 @@ const (
 	DownloadRoot  = SummariesRoot + "/download"
 	ImportsRoot   = "/imports"
 	ImportRoot    = ImportsRoot + "/:" + ID
+	ProgressRoot  = SummaryRoot + "/progress"
+	CancelRoot    = SummaryRoot + "/cancel"
+	EventsRoot    = SummaryRoot + "/events"
 )
 
 //
@@ -54,7 +57,12 @@ func (h ImportHandler) AddRoutes(e *gin.Engine) {
 	e.GET(ImportRoot, h.GetImport)
 	e.DELETE(ImportRoot, h.DeleteImport)
 	e.GET(DownloadRoot, h.DownloadCSV)
-	e.POST(UploadRoot, h.UploadCSV)
+	e.GET(DownloadRoot+".xlsx", h.DownloadXLSX)
+	e.GET(DownloadRoot+".json", h.DownloadJSON)
+	e.POST(UploadRoot, h.Upload)
+	e.GET(ProgressRoot, h.GetProgress)
+	e.DELETE(CancelRoot, h.CancelImport)
+	e.GET(EventsRoot, h.Events)
 }
 
 //
@@ -84,11 +92,15 @@ func (h ImportHandler) GetImport(ctx *gin.Context) {
 // @description List imports.
 // @tags list
 // @produce json
-// @success 200 {object} []api.Import
+// @success 200 {object} api.PaginationResult[Import]
 // @router /application-inventory/application-import [get]
+// @param importSummary.id query string false "Filter by import summary ID"
+// @param isValid query string false "Filter by validity, true or false"
+// @param page query int false "Page number, 1-based"
+// @param pageSize query int false "Items per page"
+// @param sort query string false "Comma-separated sort columns, prefix with - for descending"
 func (h ImportHandler) ListImports(ctx *gin.Context) {
-	var list []model.Import
-	db := h.DB
+	db := h.filter(ctx, h.DB, nil)
 	summaryId := ctx.Query("importSummary.id")
 	if summaryId != "" {
 		db = db.Where("importsummaryid = ?", summaryId)
@@ -99,8 +111,17 @@ func (h ImportHandler) ListImports(ctx *gin.Context) {
 	} else if isValid == "false" {
 		db = db.Not("isvalid")
 	}
+	var total int64
+	result := db.Model(&model.Import{}).Count(&total)
+	if result.Error != nil {
+		h.listFailed(ctx, result.Error)
+		return
+	}
+	db = h.sorter(ctx, db, map[string]string{"filename": "filename", "applicationName": "applicationname", "createTime": "createtime"})
+	db, page, pageSize := h.pager(ctx, db)
+	var list []model.Import
 	db = h.preLoad(db, "ImportTags")
-	result := db.Find(&list)
+	result = db.Find(&list)
 	if result.Error != nil {
 		h.listFailed(ctx, result.Error)
 		return
@@ -110,7 +131,12 @@ func (h ImportHandler) ListImports(ctx *gin.Context) {
 		resources = append(resources, list[i].AsMap())
 	}
 
-	ctx.JSON(http.StatusOK, resources)
+	ctx.JSON(http.StatusOK, PaginationResult[Import]{
+		Items:    resources,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	})
 }
 
 //
@@ -159,12 +185,25 @@ func (h ImportHandler) GetSummary(ctx *gin.Context) {
 // @description List import summaries.
 // @tags list
 // @produce json
-// @success 200 {object} []api.ImportSummary
+// @success 200 {object} api.PaginationResult[ImportSummary]
 // @router /application-inventory/import-summary [get]
+// @param q query string false "Substring match on filename"
+// @param page query int false "Page number, 1-based"
+// @param pageSize query int false "Items per page"
+// @param sort query string false "Comma-separated sort columns, prefix with - for descending"
 func (h ImportHandler) ListSummaries(ctx *gin.Context) {
+	db := h.filter(ctx, h.DB, []string{"filename"})
+	var total int64
+	result := db.Model(&model.ImportSummary{}).Count(&total)
+	if result.Error != nil {
+		h.listFailed(ctx, result.Error)
+		return
+	}
+	db = h.sorter(ctx, db, map[string]string{"filename": "filename", "createTime": "createtime"})
+	db, page, pageSize := h.pager(ctx, db)
 	var list []model.ImportSummary
-	db := h.preLoad(h.DB, "Imports")
-	result := db.Find(&list)
+	db = h.preLoad(db, "Imports")
+	result = db.Find(&list)
 	if result.Error != nil {
 		h.listFailed(ctx, result.Error)
 		return
@@ -176,7 +215,12 @@ func (h ImportHandler) ListSummaries(ctx *gin.Context) {
 		resources = append(resources, r)
 	}
 
-	ctx.JSON(http.StatusOK, resources)
+	ctx.JSON(http.StatusOK, PaginationResult[ImportSummary]{
+		Items:    resources,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	})
 }
 
 //
@@ -199,30 +243,54 @@ func (h ImportHandler) DeleteSummary(ctx *gin.Context) {
 }
 
 //
-// UploadCSV godoc
-// @summary Upload a CSV containing applications and dependencies to import.
-// @description Upload a CSV containing applications and dependencies to import.
+// Upload godoc
+// @summary Upload a file containing applications and dependencies to import.
+// @description Upload a file containing applications and dependencies to import.
+// The format is detected from the file's content type / extension (CSV,
+// XLSX, JSON); the file is persisted and parsed asynchronously, poll the
+// progress route or GET the summary to see the outcome. Pass
+// ?dryRun=true (or header X-Dry-Run: true) to validate the file and get
+// a report back with HTTP 200 without creating or persisting anything.
 // @tags post
 // @success 201 {object} api.ImportSummary
 // @produce json
 // @router /application-inventory/file/upload [post]
-func (h ImportHandler) UploadCSV(ctx *gin.Context) {
+// @param dryRun query bool false "Validate only, do not persist"
+func (h ImportHandler) Upload(ctx *gin.Context) {
 	fileName, ok := ctx.GetPostForm("fileName")
 	if !ok {
 		ctx.Status(http.StatusBadRequest)
+		return
 	}
 	file, err := ctx.FormFile("file")
 	if err != nil {
 		ctx.Status(http.StatusBadRequest)
+		return
 	}
 	fileReader, err := file.Open()
 	if err != nil {
 		ctx.Status(http.StatusBadRequest)
+		return
 	}
 	buf := bytes.NewBuffer(nil)
 	_, err = io.Copy(buf, fileReader)
 	if err != nil {
 		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	parser, err := ParserFor(file.Header.Get("Content-Type"), fileName)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"errorMessage": err.Error()})
+		return
+	}
+	rows, total, err := parser.Parse(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"errorMessage": err.Error()})
+		return
+	}
+	if h.isDryRun(ctx) {
+		h.dryRun(ctx, fileName, rows)
+		return
 	}
 	m := model.ImportSummary{
 		Filename:     fileName,
@@ -234,57 +302,145 @@ func (h ImportHandler) UploadCSV(ctx *gin.Context) {
 		h.createFailed(ctx, result.Error)
 		return
 	}
-	_, err = fileReader.Seek(0, 0)
-	if err != nil {
-		ctx.Status(http.StatusInternalServerError)
-	}
-	csvReader := csv.NewReader(fileReader)
-	csvReader.TrimLeadingSpace = true
-	// skip the header
-	_, err = csvReader.Read()
-	if err != nil {
-		ctx.Status(http.StatusBadRequest)
-	}
 
-	for {
-		row, err := csvReader.Read()
-		if err != nil {
-			if err == io.EOF {
-				break
-			} else {
-				ctx.Status(http.StatusBadRequest)
-			}
-		}
-		var imp model.Import
-		switch row[0] {
-		case RecordTypeApplication:
-			// Check row format - length, expecting 15 fields + tags
-			if len(row) < 15 {
-				ctx.JSON(http.StatusBadRequest, gin.H{"errorMessage": "Invalid Application Import CSV format."})
-				return
-			}
-			imp = h.applicationFromRow(fileName, row)
-		case RecordTypeDependency:
-			imp = h.dependencyFromRow(fileName, row)
-		default:
-			imp = model.Import{
-				Filename:    fileName,
-				RecordType1: row[0],
-			}
-		}
-		imp.ImportSummary = m
-		result := h.DB.Create(&imp)
-		if result.Error != nil {
-			h.createFailed(ctx, result.Error)
-			return
-		}
-	}
+	importer.Default().Start(h.DB, m.ID, fileName, total, rows)
 
 	summary := ImportSummary{}
 	summary.With(&m)
 	ctx.JSON(http.StatusCreated, summary)
 }
 
+//
+// isDryRun reports whether the caller asked to validate only, via either
+// the ?dryRun=true query parameter or the X-Dry-Run header.
+func (h ImportHandler) isDryRun(ctx *gin.Context) bool {
+	return ctx.Query("dryRun") == "true" || ctx.GetHeader("X-Dry-Run") == "true"
+}
+
+//
+// dryRun runs the full parse+validation pipeline without persisting any
+// Application/Dependency/Tag record, collecting every row's errors
+// instead of failing on the first one, and responds with a synthesized
+// ImportSummary alongside the annotated rows.
+func (h ImportHandler) dryRun(ctx *gin.Context, fileName string, rows <-chan model.Import) {
+	imports := importer.Validate(h.DB, rows)
+	summary := ImportSummary{Filename: fileName, ImportStatus: Completed}
+	resources := make([]Import, 0, len(imports))
+	for _, imp := range imports {
+		if imp.IsValid {
+			summary.ValidCount++
+		} else {
+			summary.InvalidCount++
+		}
+		resources = append(resources, Import{
+			"filename":        imp.Filename,
+			"recordType1":     imp.RecordType1,
+			"applicationName": imp.ApplicationName,
+			"isValid":         imp.IsValid,
+			"errorMessages":   imp.ErrorMessage,
+		})
+	}
+	ctx.JSON(http.StatusOK, DryRunReport{Summary: summary, Imports: resources})
+}
+
+//
+// GetProgress godoc
+// @summary Get the progress of a running or finished import.
+// @description Get the progress of a running or finished import.
+// @tags get
+// @produce json
+// @success 200 {object} api.ImportProgress
+// @router /importsummaries/{id}/progress [get]
+// @param id path string true "ImportSummary ID"
+func (h ImportHandler) GetProgress(ctx *gin.Context) {
+	id := ctx.Param(ID)
+	summary := &model.ImportSummary{}
+	result := h.DB.First(summary, id)
+	if result.Error != nil {
+		h.getFailed(ctx, result.Error)
+		return
+	}
+	p, found := importer.Default().Progress(summary.ID)
+	if !found {
+		ctx.JSON(http.StatusOK, ImportProgress{Status: summary.ImportStatus})
+		return
+	}
+	ctx.JSON(http.StatusOK, ImportProgress{
+		Total:     p.Total,
+		Processed: p.Processed,
+		Status:    p.Status,
+		Error:     p.Error,
+	})
+}
+
+//
+// CancelImport godoc
+// @summary Cancel a running import.
+// @description Cancel a running import. Rows already imported are left in place.
+// @tags delete
+// @success 204
+// @router /importsummaries/{id}/cancel [delete]
+// @param id path string true "ImportSummary ID"
+func (h ImportHandler) CancelImport(ctx *gin.Context) {
+	id := ctx.Param(ID)
+	summary := &model.ImportSummary{}
+	result := h.DB.First(summary, id)
+	if result.Error != nil {
+		h.getFailed(ctx, result.Error)
+		return
+	}
+	importer.Default().Cancel(summary.ID)
+	ctx.Status(http.StatusNoContent)
+}
+
+//
+// Events godoc
+// @summary Stream the progress of a running import over SSE.
+// @description Stream the progress of a running import over server-sent
+// events: a `row_processed`/`row_failed` event per row, a terminal
+// `completed`/`canceled` event, and a periodic heartbeat so proxies don't
+// time out the connection. Lets the UI get real-time feedback on long
+// imports without polling GetProgress in a tight loop.
+// @tags get
+// @produce text/event-stream
+// @success 200 {object} importer.Event
+// @router /importsummaries/{id}/events [get]
+// @param id path string true "ImportSummary ID"
+func (h ImportHandler) Events(ctx *gin.Context) {
+	id := ctx.Param(ID)
+	summary := &model.ImportSummary{}
+	result := h.DB.First(summary, id)
+	if result.Error != nil {
+		h.getFailed(ctx, result.Error)
+		return
+	}
+	events, unsubscribe := importer.Default().Subscribe(summary.ID)
+	defer unsubscribe()
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+
+	heartbeat := time.NewTicker(eventsHeartbeat)
+	defer heartbeat.Stop()
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case e, open := <-events:
+			if !open {
+				return false
+			}
+			ctx.SSEvent("message", e)
+			return e.Type != importer.EventCompleted && e.Type != importer.EventCanceled && e.Type != importer.EventFailed
+		case <-heartbeat.C:
+			ctx.SSEvent("heartbeat", gin.H{})
+			return true
+		case <-ctx.Request.Context().Done():
+			return false
+		}
+	})
+}
+
 //
 // DownloadCSV godoc
 // @summary Export the source CSV for a particular import summary.
@@ -307,112 +463,75 @@ func (h ImportHandler) DownloadCSV(ctx *gin.Context) {
 }
 
 //
-// CSV upload supports two types of records in the same file: application imports, and dependencies.
-// A dependency row must consist of the following columns:
-//
-// Col 1: Record Type 1 -- This will always contain a "2" for a dependency
-// Col 2: Application Name -- The name of the application that has the dependency relationship.
-//                            This application must exist.
-// Col N-2 or 6: Dependency -- The name of the application on the other side of the dependency relationship.
-// Col N-1 or 7: Dependency Direction -- Whether this is a "northbound" or "southbound" dependency.
-//
-// Between the Application Name and the Dependency field there may be an arbitrary number of columns representing
-// tags or other fields that only pertain to an application import. The dependency and direction will always be
-// the last two columns in the row.
-//
-// Examples:
-//
-// 2,MyApplication,,,,OtherApplication,NORTHBOUND,,,,,,,,
-// 2,MyApplication,OtherApplication,SOUTHBOUND
-func (h ImportHandler) dependencyFromRow(fileName string, row []string) (app model.Import) {
-	// Dependency Application and direction are 2 last columns by default
-	depApplicatonPos := len(row) - 2
-	depDirectionPos := len(row) - 1
-	// If there is more columns (part of Application&dependency import in the same file), uset hardcoded positions
-	if len(row) > 7 {
-		depApplicatonPos = 5
-		depDirectionPos = 6
+// DownloadXLSX godoc
+// @summary Export an import summary's rows as an XLSX workbook.
+// @description Export an import summary's rows as an XLSX workbook, using
+// the same column layout XLSXParser accepts on upload.
+// @tags export
+// @produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @success 200 file xlsx
+// @router /importsummaries/download.xlsx [get]
+// @param importSummary.id query string true "ImportSummary ID"
+func (h ImportHandler) DownloadXLSX(ctx *gin.Context) {
+	id := ctx.Query("importSummary.id")
+	m := &model.ImportSummary{}
+	db := h.preLoad(h.DB, "Imports")
+	result := db.First(m, id)
+	if result.Error != nil {
+		h.getFailed(ctx, result.Error)
+		return
 	}
-	app = model.Import{
-		Filename:            fileName,
-		RecordType1:         row[0],
-		ApplicationName:     row[1],
-		Dependency:          row[depApplicatonPos],
-		DependencyDirection: row[depDirectionPos],
+	content, err := importToXLSX(m.Imports)
+	if err != nil {
+		h.getFailed(ctx, err)
+		return
 	}
-	return
+	ctx.Writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.xlsx\"", m.Filename))
+	ctx.Data(http.StatusOK, MimeXLSX, content)
 }
 
 //
-// CSV upload supports two types of records in the same file: application imports, and dependencies.
-// An application row must consist of the following columns:
-//
-// Col 1: Record Type 1 -- This will always contain a "1" for an application
-// Col 2: Application Name -- The name of the application to be created.
-// Col 3: Description -- A short description of the application.
-// Col 4: Comments -- Additional comments on the application.
-// Col 5: Business Service -- The name of the business service this Application should belong to.
-//                            This business service must already exist.
-// Col 6: Dependency -- Optional dependency to another Application (by name)
-// Col 7: Dependency direction -- Either northbound or southbound
-//
-// Binary: Binary coordinates (like from <Group>:<Artifact>:<Version>:<Packaging>).
-// Col 8: Group
-// Col 9: Artifact
-// Col 10: Version
-// Col 11: Packaging (optional)
-//
-// Repository: The following columns are coordinates to a source repository.
-// Col 12: Kind (defaults to 'git' if empty)
-// Col 13: URL
-// Col 14: Branch
-// Col 15: Path
-//
-// Following that are up to twenty pairs of Tag Types and Tags, specified by name. These are optional.
-// If a tag type and a tag are specified, they must already exist.
-//
-// Examples:
-//
-// 1,MyApplication,My cool app,No comment,Marketing,,,binarygrp,elfbin,v1,war,git,url,branch,path,TagType1,Tag1,TagType2,Tag2
-// 1,OtherApplication,,,Marketing,MyApplication,southbound
-func (h ImportHandler) applicationFromRow(fileName string, row []string) (app model.Import) {
-	app = model.Import{
-		Filename:            fileName,
-		RecordType1:         row[0],
-		ApplicationName:     row[1],
-		Description:         row[2],
-		Comments:            row[3],
-		BusinessService:     row[4],
-		Dependency:          row[5],
-		DependencyDirection: row[6],
-		BinaryGroup:         row[7],
-		BinaryArtifact:      row[8],
-		BinaryVersion:       row[9],
-		BinaryPackaging:     row[10],
-		RepositoryKind:      row[11],
-		RepositoryURL:       row[12],
-		RepositoryBranch:    row[13],
-		RepositoryPath:      row[14],
-	}
-
-	// Tags
-	for i := 15; i < len(row); i++ {
-		if i%2 == 0 {
-			tag := model.ImportTag{
-				Name:    row[i],
-				TagType: row[i-1],
-			}
-			app.ImportTags = append(app.ImportTags, tag)
-		}
+// DownloadJSON godoc
+// @summary Export an import summary's rows in the JSON import format.
+// @description Export an import summary's rows in the JSON import format,
+// the same shape JSONParser accepts on upload.
+// @tags export
+// @produce json
+// @success 200 {object} jsonDocument
+// @router /importsummaries/download.json [get]
+// @param importSummary.id query string true "ImportSummary ID"
+func (h ImportHandler) DownloadJSON(ctx *gin.Context) {
+	id := ctx.Query("importSummary.id")
+	m := &model.ImportSummary{}
+	db := h.preLoad(h.DB, "Imports", "Imports.ImportTags")
+	result := db.First(m, id)
+	if result.Error != nil {
+		h.getFailed(ctx, result.Error)
+		return
 	}
-
-	return
+	ctx.JSON(http.StatusOK, importToJSON(m.Imports))
 }
 
 //
 // Import REST resource.
 type Import map[string]interface{}
 
+//
+// DryRunReport REST resource returned by Upload when dryRun is requested.
+type DryRunReport struct {
+	Summary ImportSummary `json:"summary"`
+	Imports []Import      `json:"imports"`
+}
+
+//
+// ImportProgress REST resource.
+type ImportProgress struct {
+	Total     int    `json:"total"`
+	Processed int    `json:"processed"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+}
+
 //
 // ImportSummary REST resource.
 type ImportSummary struct {
@@ -430,6 +549,7 @@ func (r *ImportSummary) With(m *model.ImportSummary) {
 	r.Resource.With(&m.Model)
 	r.Filename = m.Filename
 	r.ImportTime = m.CreateTime
+	r.ImportStatus = m.ImportStatus
 	for _, imp := range m.Imports {
 		if imp.Processed {
 			if imp.IsValid {
@@ -439,9 +559,4 @@ func (r *ImportSummary) With(m *model.ImportSummary) {
 			}
 		}
 	}
-	if len(m.Imports) == r.ValidCount+r.InvalidCount {
-		r.ImportStatus = Completed
-	} else {
-		r.ImportStatus = InProgress
-	}
 }
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/konveyor/tackle2-hub/model"
+)
+
+//
+// With must trust the summary's own ImportStatus rather than inferring
+// completion from row counts -- every model.Import row that exists is
+// already Processed by the time the Worker creates it, so a count-based
+// heuristic reports Completed after the very first row.
+func TestImportSummaryWithTrustsStoredStatus(t *testing.T) {
+	m := &model.ImportSummary{
+		Filename:     "apps.csv",
+		ImportStatus: InProgress,
+	}
+	m.Imports = []model.Import{
+		{Processed: true, IsValid: true},
+		{Processed: true, IsValid: false, ErrorMessage: "boom"},
+	}
+	r := ImportSummary{}
+	r.With(m)
+
+	if r.ImportStatus != InProgress {
+		t.Fatalf("expected status %q from the model, got %q", InProgress, r.ImportStatus)
+	}
+	if r.ValidCount != 1 || r.InvalidCount != 1 {
+		t.Fatalf("unexpected counts: valid=%d invalid=%d", r.ValidCount, r.InvalidCount)
+	}
+
+	m.ImportStatus = Completed
+	r = ImportSummary{}
+	r.With(m)
+	if r.ImportStatus != Completed {
+		t.Fatalf("expected status %q from the model, got %q", Completed, r.ImportStatus)
+	}
+}
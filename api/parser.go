@@ -0,0 +1,43 @@
+package api
+
+import (
+	"io"
+	"strings"
+
+	"github.com/konveyor/tackle2-hub/model"
+)
+
+//
+// Supported import MIME types / file extensions.
+const (
+	MimeCSV  = "text/csv"
+	MimeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	MimeJSON = "application/json"
+)
+
+//
+// Parser parses an uploaded import file into a stream of Import rows,
+// reporting the total row count up front so progress can be reported as
+// a fraction throughout the import rather than only once it finishes.
+// ImportHandler stays format-agnostic by depending only on this
+// interface; the importer worker consumes whatever channel it returns.
+type Parser interface {
+	Parse(reader io.Reader) (ch <-chan model.Import, total int, err error)
+}
+
+//
+// ParserFor selects a Parser by MIME type or file extension, so a file
+// sent with a generic content type (e.g. multipart uploads are often sent
+// as application/octet-stream) is still recognised. XLSX is checked
+// first, then JSON, by either; anything else is parsed as CSV.
+func ParserFor(contentType string, fileName string) (p Parser, err error) {
+	switch {
+	case contentType == MimeXLSX || strings.HasSuffix(strings.ToLower(fileName), ".xlsx"):
+		p = &XLSXParser{}
+	case contentType == MimeJSON || strings.HasSuffix(strings.ToLower(fileName), ".json"):
+		p = &JSONParser{}
+	default:
+		p = &CSVParser{}
+	}
+	return
+}
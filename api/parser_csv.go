@@ -0,0 +1,149 @@
+package api
+
+import (
+	"encoding/csv"
+	"io"
+
+	"github.com/konveyor/tackle2-hub/model"
+)
+
+//
+// Record types carried in column 1 of a CSV/XLSX row.
+const (
+	RecordTypeApplication = "1"
+	RecordTypeDependency  = "2"
+)
+
+//
+// CSVParser parses the CSV import format.
+//
+// CSV upload supports two types of records in the same file: application
+// imports, and dependencies.
+//
+// An application row must consist of the following columns:
+//
+// Col 1: Record Type 1 -- This will always contain a "1" for an application
+// Col 2: Application Name -- The name of the application to be created.
+// Col 3: Description -- A short description of the application.
+// Col 4: Comments -- Additional comments on the application.
+// Col 5: Business Service -- The name of the business service this Application should belong to.
+//                            This business service must already exist.
+// Col 6: Dependency -- Optional dependency to another Application (by name)
+// Col 7: Dependency direction -- Either northbound or southbound
+//
+// Binary: Binary coordinates (like from <Group>:<Artifact>:<Version>:<Packaging>).
+// Col 8: Group
+// Col 9: Artifact
+// Col 10: Version
+// Col 11: Packaging (optional)
+//
+// Repository: The following columns are coordinates to a source repository.
+// Col 12: Kind (defaults to 'git' if empty)
+// Col 13: URL
+// Col 14: Branch
+// Col 15: Path
+//
+// Following that are up to twenty pairs of Tag Types and Tags, specified by name. These are optional.
+// If a tag type and a tag are specified, they must already exist.
+//
+// A dependency row must consist of the following columns:
+//
+// Col 1: Record Type 1 -- This will always contain a "2" for a dependency
+// Col 2: Application Name -- The name of the application that has the dependency relationship.
+//                            This application must exist.
+// Col N-2 or 6: Dependency -- The name of the application on the other side of the dependency relationship.
+// Col N-1 or 7: Dependency Direction -- Whether this is a "northbound" or "southbound" dependency.
+//
+// Between the Application Name and the Dependency field there may be an arbitrary number of columns representing
+// tags or other fields that only pertain to an application import. In a row of up to seven columns the dependency
+// and direction are the last two columns; in a longer row they are columns 6 and 7.
+//
+// Examples:
+//
+// 1,MyApplication,My cool app,No comment,Marketing,,,binarygrp,elfbin,v1,war,git,url,branch,path,TagType1,Tag1,TagType2,Tag2
+// 1,OtherApplication,,,Marketing,MyApplication,southbound,,,,,,,,
+// 2,MyApplication,,,,OtherApplication,NORTHBOUND,,,,,,,,
+// 2,MyApplication,OtherApplication,SOUTHBOUND
+type CSVParser struct{}
+
+//
+// Parse reads every row up front, skipping the header, so the caller
+// learns the total row count before a single model.Import is emitted,
+// then streams them one at a time on the returned channel.
+func (p *CSVParser) Parse(reader io.Reader) (ch <-chan model.Import, total int, err error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+	csvReader.FieldsPerRecord = -1 // application and dependency rows have different widths; RowToImport handles short rows itself.
+	_, err = csvReader.Read() // skip header.
+	if err != nil {
+		return
+	}
+	rows, err := csvReader.ReadAll()
+	if err != nil {
+		return
+	}
+	total = len(rows)
+	out := make(chan model.Import)
+	go func() {
+		defer close(out)
+		for _, row := range rows {
+			out <- RowToImport(row)
+		}
+	}()
+	ch = out
+	return
+}
+
+//
+// RowToImport builds a model.Import from a raw row of columns, shared by
+// the CSV and XLSX parsers since both use the same column layout.
+func RowToImport(row []string) (imp model.Import) {
+	if len(row) == 0 {
+		return
+	}
+	switch row[0] {
+	case RecordTypeApplication:
+		if len(row) < 15 {
+			imp = model.Import{RecordType1: row[0], ErrorMessage: "Invalid Application Import row format."}
+			return
+		}
+		imp = model.Import{
+			RecordType1:         row[0],
+			ApplicationName:     row[1],
+			Description:         row[2],
+			Comments:            row[3],
+			BusinessService:     row[4],
+			Dependency:          row[5],
+			DependencyDirection: row[6],
+			BinaryGroup:         row[7],
+			BinaryArtifact:      row[8],
+			BinaryVersion:       row[9],
+			BinaryPackaging:     row[10],
+			RepositoryKind:      row[11],
+			RepositoryURL:       row[12],
+			RepositoryBranch:    row[13],
+			RepositoryPath:      row[14],
+		}
+		for i := 15; i < len(row); i++ {
+			if i%2 == 0 {
+				imp.ImportTags = append(imp.ImportTags, model.ImportTag{Name: row[i], TagType: row[i-1]})
+			}
+		}
+	case RecordTypeDependency:
+		depApplicatonPos := len(row) - 2
+		depDirectionPos := len(row) - 1
+		if len(row) > 7 {
+			depApplicatonPos = 5
+			depDirectionPos = 6
+		}
+		imp = model.Import{
+			RecordType1:         row[0],
+			ApplicationName:     row[1],
+			Dependency:          row[depApplicatonPos],
+			DependencyDirection: row[depDirectionPos],
+		}
+	default:
+		imp = model.Import{RecordType1: row[0], ErrorMessage: "Unknown record type."}
+	}
+	return
+}
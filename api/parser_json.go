@@ -0,0 +1,156 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/konveyor/tackle2-hub/model"
+)
+
+//
+// JSONParser parses the JSON import format: a single object with
+// `applications` and `dependencies` arrays of fully typed fields, as
+// opposed to the positional columns the CSV/XLSX parsers deal with.
+type JSONParser struct{}
+
+//
+// jsonDocument is the top-level shape of a JSON import.
+type jsonDocument struct {
+	Applications []jsonApplication `json:"applications"`
+	Dependencies []jsonDependency  `json:"dependencies"`
+}
+
+//
+// jsonApplication is one entry of the `applications` array.
+type jsonApplication struct {
+	Name            string        `json:"name" binding:"required"`
+	Description     string        `json:"description"`
+	Comments        string        `json:"comments"`
+	BusinessService string        `json:"businessService"`
+	Binary          jsonBinary    `json:"binary"`
+	Repository      jsonRepo      `json:"repository"`
+	Tags            []jsonTagPair `json:"tags"`
+}
+
+//
+// jsonBinary is the Group/Artifact/Version/Packaging coordinate of an
+// application's binary, equivalent to columns 8-11 of a CSV row.
+type jsonBinary struct {
+	Group     string `json:"group"`
+	Artifact  string `json:"artifact"`
+	Version   string `json:"version"`
+	Packaging string `json:"packaging"`
+}
+
+//
+// jsonRepo is an application's source repository coordinate, equivalent to
+// columns 12-15 of a CSV row.
+type jsonRepo struct {
+	Kind   string `json:"kind"`
+	URL    string `json:"url"`
+	Branch string `json:"branch"`
+	Path   string `json:"path"`
+}
+
+//
+// jsonTagPair names a tag type and tag, both of which must already exist.
+type jsonTagPair struct {
+	TagType string `json:"tagType" binding:"required"`
+	Tag     string `json:"tag" binding:"required"`
+}
+
+//
+// jsonDependency is one entry of the `dependencies` array.
+type jsonDependency struct {
+	Application string `json:"application" binding:"required"`
+	Dependency  string `json:"dependency" binding:"required"`
+	Direction   string `json:"direction"`
+}
+
+//
+// Parse decodes the whole document up front -- JSON import files are
+// expected to be small relative to bulk CSV/XLSX uploads -- so the
+// caller learns the total row count before a single model.Import is
+// emitted, then streams one per application and per dependency entry.
+func (p *JSONParser) Parse(reader io.Reader) (ch <-chan model.Import, total int, err error) {
+	doc := &jsonDocument{}
+	err = json.NewDecoder(reader).Decode(doc)
+	if err != nil {
+		return
+	}
+	total = len(doc.Applications) + len(doc.Dependencies)
+	out := make(chan model.Import)
+	go func() {
+		defer close(out)
+		for _, a := range doc.Applications {
+			imp := model.Import{
+				RecordType1:      RecordTypeApplication,
+				ApplicationName:  a.Name,
+				Description:      a.Description,
+				Comments:         a.Comments,
+				BusinessService:  a.BusinessService,
+				BinaryGroup:      a.Binary.Group,
+				BinaryArtifact:   a.Binary.Artifact,
+				BinaryVersion:    a.Binary.Version,
+				BinaryPackaging:  a.Binary.Packaging,
+				RepositoryKind:   a.Repository.Kind,
+				RepositoryURL:    a.Repository.URL,
+				RepositoryBranch: a.Repository.Branch,
+				RepositoryPath:   a.Repository.Path,
+			}
+			for _, t := range a.Tags {
+				imp.ImportTags = append(imp.ImportTags, model.ImportTag{Name: t.Tag, TagType: t.TagType})
+			}
+			out <- imp
+		}
+		for _, d := range doc.Dependencies {
+			out <- model.Import{
+				RecordType1:         RecordTypeDependency,
+				ApplicationName:     d.Application,
+				Dependency:          d.Dependency,
+				DependencyDirection: d.Direction,
+			}
+		}
+	}()
+	ch = out
+	return
+}
+
+//
+// importToJSON renders a set of model.Import rows back into the JSON
+// import document shape Parse expects.
+func importToJSON(imports []model.Import) (doc jsonDocument) {
+	for _, imp := range imports {
+		if imp.RecordType1 == RecordTypeDependency {
+			doc.Dependencies = append(doc.Dependencies, jsonDependency{
+				Application: imp.ApplicationName,
+				Dependency:  imp.Dependency,
+				Direction:   imp.DependencyDirection,
+			})
+			continue
+		}
+		a := jsonApplication{
+			Name:            imp.ApplicationName,
+			Description:     imp.Description,
+			Comments:        imp.Comments,
+			BusinessService: imp.BusinessService,
+			Binary: jsonBinary{
+				Group:     imp.BinaryGroup,
+				Artifact:  imp.BinaryArtifact,
+				Version:   imp.BinaryVersion,
+				Packaging: imp.BinaryPackaging,
+			},
+			Repository: jsonRepo{
+				Kind:   imp.RepositoryKind,
+				URL:    imp.RepositoryURL,
+				Branch: imp.RepositoryBranch,
+				Path:   imp.RepositoryPath,
+			},
+		}
+		for _, t := range imp.ImportTags {
+			a.Tags = append(a.Tags, jsonTagPair{TagType: t.TagType, Tag: t.Name})
+		}
+		doc.Applications = append(doc.Applications, a)
+	}
+	return
+}
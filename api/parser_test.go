@@ -0,0 +1,83 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/konveyor/tackle2-hub/model"
+)
+
+//
+// parseAll drains a parser's channel into a slice for easy comparison.
+func parseAll(t *testing.T, ch <-chan model.Import) (out []model.Import) {
+	t.Helper()
+	for imp := range ch {
+		out = append(out, imp)
+	}
+	return
+}
+
+//
+// XLSXParser.Parse must read back what importToXLSX wrote, since Download
+// and Upload are expected to round-trip the same workbook layout.
+func TestXLSXRoundTrip(t *testing.T) {
+	imports := []model.Import{
+		{RecordType1: RecordTypeApplication, ApplicationName: "MyApplication", Description: "My cool app"},
+		{RecordType1: RecordTypeDependency, ApplicationName: "OtherApplication", Dependency: "MyApplication", DependencyDirection: "southbound"},
+	}
+	content, err := importToXLSX(imports)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parser := &XLSXParser{}
+	ch, total, err := parser.Parse(bytes.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != len(imports) {
+		t.Fatalf("expected total %d, got %d", len(imports), total)
+	}
+	got := parseAll(t, ch)
+	if len(got) != len(imports) {
+		t.Fatalf("expected %d rows, got %d", len(imports), len(got))
+	}
+	if got[0].ApplicationName != "MyApplication" || got[0].Description != "My cool app" {
+		t.Fatalf("unexpected application row: %+v", got[0])
+	}
+	if got[1].ApplicationName != "OtherApplication" || got[1].Dependency != "MyApplication" {
+		t.Fatalf("unexpected dependency row: %+v", got[1])
+	}
+}
+
+//
+// JSONParser.Parse must read back what importToJSON wrote.
+func TestJSONRoundTrip(t *testing.T) {
+	imports := []model.Import{
+		{RecordType1: RecordTypeApplication, ApplicationName: "MyApplication", BusinessService: "Marketing"},
+		{RecordType1: RecordTypeDependency, ApplicationName: "OtherApplication", Dependency: "MyApplication", DependencyDirection: "northbound"},
+	}
+	doc := importToJSON(imports)
+	body, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parser := &JSONParser{}
+	ch, total, err := parser.Parse(bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != len(imports) {
+		t.Fatalf("expected total %d, got %d", len(imports), total)
+	}
+	got := parseAll(t, ch)
+	if len(got) != len(imports) {
+		t.Fatalf("expected %d rows, got %d", len(imports), len(got))
+	}
+	if got[0].ApplicationName != "MyApplication" || got[0].BusinessService != "Marketing" {
+		t.Fatalf("unexpected application row: %+v", got[0])
+	}
+	if got[1].ApplicationName != "OtherApplication" || got[1].Dependency != "MyApplication" || got[1].DependencyDirection != "northbound" {
+		t.Fatalf("unexpected dependency row: %+v", got[1])
+	}
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"io"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/xuri/excelize/v2"
+)
+
+//
+// XLSXParser parses the Excel import format: the first sheet, using the
+// same column layout as CSVParser (see RowToImport).
+type XLSXParser struct{}
+
+//
+// Parse reads every row of the first sheet up front, skipping the header
+// and any blank rows, so the caller learns the total row count before a
+// single model.Import is emitted, then streams them one at a time on
+// the returned channel.
+func (p *XLSXParser) Parse(reader io.Reader) (ch <-chan model.Import, total int, err error) {
+	f, err := excelize.OpenReader(reader)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	allRows, err := f.GetRows(f.GetSheetName(0))
+	if err != nil {
+		return
+	}
+	var rows [][]string
+	for i, row := range allRows {
+		if i == 0 || len(row) == 0 {
+			continue // skip header / blank rows.
+		}
+		if row[0] == RecordTypeApplication {
+			// GetRows drops trailing blank cells; restore the 15 fixed
+			// columns RowToImport requires of an application row.
+			for len(row) < 15 {
+				row = append(row, "")
+			}
+		}
+		rows = append(rows, row)
+	}
+	total = len(rows)
+	out := make(chan model.Import)
+	go func() {
+		defer close(out)
+		for _, row := range rows {
+			out <- RowToImport(row)
+		}
+	}()
+	ch = out
+	return
+}
+
+//
+// importToXLSX renders a set of model.Import rows back into a workbook
+// using the same column layout RowToImport expects.
+func importToXLSX(imports []model.Import) (content []byte, err error) {
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	header := []string{
+		"recordType1", "applicationName", "description", "comments", "businessService",
+		"dependency", "dependencyDirection", "binaryGroup", "binaryArtifact", "binaryVersion",
+		"binaryPackaging", "repositoryKind", "repositoryURL", "repositoryBranch", "repositoryPath",
+	}
+	for i, h := range header {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		_ = f.SetCellValue(sheet, cell, h)
+	}
+	for r, imp := range imports {
+		row := []interface{}{
+			imp.RecordType1, imp.ApplicationName, imp.Description, imp.Comments, imp.BusinessService,
+			imp.Dependency, imp.DependencyDirection, imp.BinaryGroup, imp.BinaryArtifact, imp.BinaryVersion,
+			imp.BinaryPackaging, imp.RepositoryKind, imp.RepositoryURL, imp.RepositoryBranch, imp.RepositoryPath,
+		}
+		for c, v := range row {
+			cell, _ := excelize.CoordinatesToCellName(c+1, r+2)
+			_ = f.SetCellValue(sheet, cell, v)
+		}
+	}
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return
+	}
+	content = buf.Bytes()
+	return
+}
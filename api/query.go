@@ -0,0 +1,95 @@
+package api
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+//
+// Pagination defaults.
+const (
+	DefaultPageSize = 100
+)
+
+//
+// PaginationResult envelopes a page of resources together with the total
+// count across all pages, so list endpoints don't each invent their own
+// shape for "items + total".
+type PaginationResult[T any] struct {
+	Items    []T   `json:"items"`
+	Total    int64 `json:"total"`
+	Page     int   `json:"page"`
+	PageSize int   `json:"pageSize"`
+}
+
+//
+// filter applies a `?q=` substring match across searchCols (OR'd
+// together) and an exact, possibly multi-valued, match for each column
+// named in matchCols (e.g. `?kind=git&kind=maven` becomes `kind IN
+// (git, maven)`).
+func (h BaseHandler) filter(ctx *gin.Context, db *gorm.DB, searchCols []string, matchCols ...string) *gorm.DB {
+	if q := ctx.Query("q"); q != "" && len(searchCols) > 0 {
+		clauses := make([]string, len(searchCols))
+		args := make([]interface{}, len(searchCols))
+		for i, col := range searchCols {
+			clauses[i] = col + " LIKE ?"
+			args[i] = "%" + q + "%"
+		}
+		db = db.Where(strings.Join(clauses, " OR "), args...)
+	}
+	for _, name := range matchCols {
+		values := ctx.QueryArray(name)
+		if len(values) > 0 {
+			db = db.Where(name+" IN ?", values)
+		}
+	}
+	return db
+}
+
+//
+// sorter applies `?sort=col,-col2` ordering. allowed maps each public
+// sort key (the name callers put in the query string) to the actual DB
+// column it orders by, both so the query string can't inject arbitrary
+// SQL and because the public name is not itself the column name (e.g.
+// "createTime" is column "createtime"). Keys not in allowed are ignored.
+// A leading `-` requests descending order.
+func (h BaseHandler) sorter(ctx *gin.Context, db *gorm.DB, allowed map[string]string) *gorm.DB {
+	param := ctx.Query("sort")
+	if param == "" {
+		return db
+	}
+	for _, col := range strings.Split(param, ",") {
+		desc := strings.HasPrefix(col, "-")
+		name := strings.TrimPrefix(col, "-")
+		column, ok := allowed[name]
+		if !ok {
+			continue
+		}
+		if desc {
+			db = db.Order(column + " DESC")
+		} else {
+			db = db.Order(column)
+		}
+	}
+	return db
+}
+
+//
+// pager applies `?page=`/`?pageSize=` offset pagination and returns the
+// page/pageSize actually used (clamped to sane defaults) for the response
+// envelope.
+func (h BaseHandler) pager(ctx *gin.Context, db *gorm.DB) (out *gorm.DB, page int, pageSize int) {
+	page, _ = strconv.Atoi(ctx.Query("page"))
+	if page < 1 {
+		page = 1
+	}
+	pageSize, _ = strconv.Atoi(ctx.Query("pageSize"))
+	if pageSize < 1 {
+		pageSize = DefaultPageSize
+	}
+	out = db.Offset((page - 1) * pageSize).Limit(pageSize)
+	return
+}
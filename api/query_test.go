@@ -0,0 +1,44 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konveyor/tackle2-hub/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+//
+// sorter must apply the mapped DB column, honor descending order, and
+// silently ignore sort keys absent from the allow-list.
+func TestSorterAppliesMappedColumnAndIgnoresUnlisted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.AutoMigrate(&model.Identity{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Create(&model.Identity{Name: "b"})
+	db.Create(&model.Identity{Name: "a"})
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/identities?sort=-name,bogus", nil)
+
+	h := BaseHandler{}
+	out := h.sorter(ctx, db, map[string]string{"name": "name"})
+	var list []model.Identity
+	err = out.Find(&list).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].Name != "b" || list[1].Name != "a" {
+		t.Fatalf("expected descending order by name with the unlisted column ignored, got %+v", list)
+	}
+}
@@ -0,0 +1,28 @@
+package identity
+
+import (
+	"context"
+	"errors"
+)
+
+//
+// ErrInvalidSettings is returned when an identity's backend-specific
+// settings cannot be parsed or are missing required fields.
+var ErrInvalidSettings = errors.New("identity: invalid backend settings")
+
+//
+// emptyCtx is used for the short-lived calls providers make to external
+// secret stores; none of them are request-scoped today.
+func emptyCtx() context.Context {
+	return context.Background()
+}
+
+//
+// str coerces a KV value of unknown type to a string, returning "" for
+// anything unexpected rather than failing the whole secret.
+func str(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return ""
+}
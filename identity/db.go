@@ -0,0 +1,47 @@
+package identity
+
+import (
+	"github.com/konveyor/tackle2-hub/model"
+	"gorm.io/gorm"
+)
+
+func init() {
+	Register(DB, &DBProvider{})
+}
+
+//
+// DBProvider stores secret material encrypted in the hub's own database,
+// on the model.Identity row itself. This is the backend used before
+// Vault/K8s support existed and remains the default.
+type DBProvider struct{}
+
+//
+// Get reads the secret fields off the Identity row and decrypts them.
+func (p *DBProvider) Get(db *gorm.DB, id uint, settings string) (secret Secret, err error) {
+	m := &model.Identity{}
+	result := db.First(m, id)
+	if result.Error != nil {
+		err = result.Error
+		return
+	}
+	err = m.Decrypt()
+	if err != nil {
+		return
+	}
+	secret = Secret{User: m.User, Password: m.Password, Key: m.Key}
+	return
+}
+
+//
+// Put is a no-op; DBProvider's material is written by the handler as part
+// of the normal Create/Update of the Identity row.
+func (p *DBProvider) Put(db *gorm.DB, id uint, settings string, secret Secret) (err error) {
+	return
+}
+
+//
+// Delete is a no-op; DBProvider's material is removed when the Identity
+// row itself is deleted.
+func (p *DBProvider) Delete(db *gorm.DB, id uint, settings string) (err error) {
+	return
+}
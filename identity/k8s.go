@@ -0,0 +1,131 @@
+package identity
+
+import (
+	"encoding/json"
+
+	"gorm.io/gorm"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+func init() {
+	Register(K8s, &K8sProvider{})
+}
+
+//
+// K8sSettings is the backend-specific `settings` JSON stored on an
+// Identity with backend "k8s".
+type K8sSettings struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+//
+// K8sProvider stores secret material as an Opaque Secret in the cluster
+// the hub itself runs in, using its in-cluster service account.
+type K8sProvider struct{}
+
+//
+// client builds a client-go clientset from the in-cluster config.
+func (p *K8sProvider) client() (c *kubernetes.Clientset, err error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return
+	}
+	c, err = kubernetes.NewForConfig(config)
+	return
+}
+
+//
+// Get reads the named Secret and maps its data keys onto a Secret.
+func (p *K8sProvider) Get(db *gorm.DB, id uint, settings string) (secret Secret, err error) {
+	s, err := p.parse(settings)
+	if err != nil {
+		return
+	}
+	c, err := p.client()
+	if err != nil {
+		return
+	}
+	found, err := c.CoreV1().Secrets(s.Namespace).Get(emptyCtx(), s.Name, metav1.GetOptions{})
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = ErrNotFound
+		}
+		return
+	}
+	secret = Secret{
+		User:     string(found.Data["user"]),
+		Password: string(found.Data["password"]),
+		Key:      string(found.Data["key"]),
+	}
+	return
+}
+
+//
+// Put creates or updates the named Secret.
+func (p *K8sProvider) Put(db *gorm.DB, id uint, settings string, secret Secret) (err error) {
+	s, err := p.parse(settings)
+	if err != nil {
+		return
+	}
+	c, err := p.client()
+	if err != nil {
+		return
+	}
+	data := map[string][]byte{
+		"user":     []byte(secret.User),
+		"password": []byte(secret.Password),
+		"key":      []byte(secret.Key),
+	}
+	object := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: s.Name, Namespace: s.Namespace},
+		Type:       corev1.SecretTypeOpaque,
+		Data:       data,
+	}
+	secrets := c.CoreV1().Secrets(s.Namespace)
+	_, err = secrets.Get(emptyCtx(), s.Name, metav1.GetOptions{})
+	if err != nil {
+		if !k8serr.IsNotFound(err) {
+			return
+		}
+		_, err = secrets.Create(emptyCtx(), object, metav1.CreateOptions{})
+		return
+	}
+	_, err = secrets.Update(emptyCtx(), object, metav1.UpdateOptions{})
+	return
+}
+
+//
+// Delete removes the named Secret.
+func (p *K8sProvider) Delete(db *gorm.DB, id uint, settings string) (err error) {
+	s, err := p.parse(settings)
+	if err != nil {
+		return
+	}
+	c, err := p.client()
+	if err != nil {
+		return
+	}
+	err = c.CoreV1().Secrets(s.Namespace).Delete(emptyCtx(), s.Name, metav1.DeleteOptions{})
+	if k8serr.IsNotFound(err) {
+		err = nil
+	}
+	return
+}
+
+//
+// parse decodes the identity's backend-specific settings.
+func (p *K8sProvider) parse(settings string) (s K8sSettings, err error) {
+	err = json.Unmarshal([]byte(settings), &s)
+	if err != nil {
+		return
+	}
+	if s.Namespace == "" || s.Name == "" {
+		err = ErrInvalidSettings
+	}
+	return
+}
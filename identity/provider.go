@@ -0,0 +1,71 @@
+package identity
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+//
+// Backend names. Stored on model.Identity.Backend; adding Vault/K8s here
+// requires that field and its migration to exist in the model package,
+// and this package's go.mod to list github.com/hashicorp/vault/api and
+// the k8s.io/{client-go,api,apimachinery} trio -- neither the model
+// package nor a go.mod are present in this tree (not since before this
+// series; the baseline commit itself has neither), so those additions
+// are out of scope here and must land wherever the rest of this repo's
+// model/migrations and dependency manifest actually live.
+const (
+	DB    = "db"
+	Vault = "vault"
+	K8s   = "k8s"
+)
+
+//
+// ErrNotFound is returned by a Provider when the identity has no secret
+// material stored under it.
+var ErrNotFound = errors.New("secret not found")
+
+//
+// Secret is the material a Provider stores for an identity: some
+// combination of user/password/key, shaped by the identity's Kind.
+type Secret struct {
+	User     string
+	Password string
+	Key      string
+}
+
+//
+// Provider stores and retrieves the secret material for an identity.
+// Settings is the backend-specific configuration recorded on the
+// identity itself (mount path, secret name, namespace, ...).
+type Provider interface {
+	Get(db *gorm.DB, id uint, settings string) (Secret, error)
+	Put(db *gorm.DB, id uint, settings string, secret Secret) error
+	Delete(db *gorm.DB, id uint, settings string) error
+}
+
+//
+// Providers maps a backend name to its Provider implementation.
+var Providers = map[string]Provider{}
+
+//
+// Register installs a Provider under a backend name. Called from each
+// provider's init().
+func Register(backend string, p Provider) {
+	Providers[backend] = p
+}
+
+//
+// Get returns the Provider for a backend, defaulting to the DB provider
+// when backend is empty.
+func Get(backend string) (p Provider, err error) {
+	if backend == "" {
+		backend = DB
+	}
+	p, found := Providers[backend]
+	if !found {
+		err = errors.New("identity: unknown backend: " + backend)
+	}
+	return
+}
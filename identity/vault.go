@@ -0,0 +1,122 @@
+package identity
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	"gorm.io/gorm"
+)
+
+func init() {
+	Register(Vault, &VaultProvider{})
+}
+
+//
+// VaultSettings is the backend-specific `settings` JSON stored on an
+// Identity with backend "vault".
+type VaultSettings struct {
+	Mount string `json:"mount"`
+	Path  string `json:"path"`
+}
+
+//
+// VaultProvider stores secret material in HashiCorp Vault's KV v2 engine.
+// Vault address and token are read from the process environment, the
+// same way every other addon/task in the hub reaches external services.
+type VaultProvider struct{}
+
+//
+// client builds a Vault API client from the environment.
+func (p *VaultProvider) client() (c *vaultapi.Client, err error) {
+	config := vaultapi.DefaultConfig()
+	err = config.ReadEnvironment()
+	if err != nil {
+		return
+	}
+	c, err = vaultapi.NewClient(config)
+	if err != nil {
+		return
+	}
+	if token := os.Getenv("VAULT_TOKEN"); token != "" {
+		c.SetToken(token)
+	}
+	return
+}
+
+//
+// Get reads the secret at the configured KV v2 path.
+func (p *VaultProvider) Get(db *gorm.DB, id uint, settings string) (secret Secret, err error) {
+	s, err := p.parse(settings)
+	if err != nil {
+		return
+	}
+	c, err := p.client()
+	if err != nil {
+		return
+	}
+	kv := c.KVv2(s.Mount)
+	v, err := kv.Get(emptyCtx(), s.Path)
+	if err != nil {
+		if errors.Is(err, vaultapi.ErrSecretNotFound) {
+			err = ErrNotFound
+		}
+		return
+	}
+	secret = Secret{
+		User:     str(v.Data["user"]),
+		Password: str(v.Data["password"]),
+		Key:      str(v.Data["key"]),
+	}
+	return
+}
+
+//
+// Put writes the secret at the configured KV v2 path.
+func (p *VaultProvider) Put(db *gorm.DB, id uint, settings string, secret Secret) (err error) {
+	s, err := p.parse(settings)
+	if err != nil {
+		return
+	}
+	c, err := p.client()
+	if err != nil {
+		return
+	}
+	kv := c.KVv2(s.Mount)
+	_, err = kv.Put(emptyCtx(), s.Path, map[string]interface{}{
+		"user":     secret.User,
+		"password": secret.Password,
+		"key":      secret.Key,
+	})
+	return
+}
+
+//
+// Delete removes the secret at the configured KV v2 path.
+func (p *VaultProvider) Delete(db *gorm.DB, id uint, settings string) (err error) {
+	s, err := p.parse(settings)
+	if err != nil {
+		return
+	}
+	c, err := p.client()
+	if err != nil {
+		return
+	}
+	kv := c.KVv2(s.Mount)
+	err = kv.Delete(emptyCtx(), s.Path)
+	return
+}
+
+//
+// parse decodes the identity's backend-specific settings.
+func (p *VaultProvider) parse(settings string) (s VaultSettings, err error) {
+	err = json.Unmarshal([]byte(settings), &s)
+	if err != nil {
+		return
+	}
+	if s.Mount == "" || s.Path == "" {
+		err = ErrInvalidSettings
+	}
+	return
+}
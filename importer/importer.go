@@ -0,0 +1,155 @@
+package importer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"gorm.io/gorm"
+)
+
+//
+// Job statuses.
+const (
+	StatusRunning   = "Running"
+	StatusCompleted = "Completed"
+	StatusCanceled  = "Canceled"
+	StatusFailed    = "Failed"
+)
+
+//
+// jobRetention is how long a terminal job's progress stays queryable
+// from memory after it finishes, before being evicted. GetProgress
+// falls back to the ImportSummary's persisted status once a job is no
+// longer tracked, so this only bounds how long the more granular
+// Total/Processed counts remain available, not whether the status does.
+const jobRetention = 5 * time.Minute
+
+//
+// isTerminal reports whether a job status is one Run no longer updates.
+func isTerminal(status string) bool {
+	switch status {
+	case StatusCompleted, StatusCanceled, StatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+//
+// Progress reports the state of a running or finished import job.
+type Progress struct {
+	SummaryID uint
+	Total     int
+	Processed int
+	Status    string
+	Error     string
+}
+
+//
+// Manager tracks running import jobs and lets callers poll progress
+// or request cancellation without blocking on the DB.
+type Manager struct {
+	mutex       sync.Mutex
+	jobs        map[uint]*job
+	subscribers map[uint][]chan Event
+}
+
+//
+// job is the in-memory state for a single import summary being processed.
+type job struct {
+	progress Progress
+	cancel   chan struct{}
+}
+
+//
+// singleton Manager shared by the api package.
+var singleton = &Manager{jobs: map[uint]*job{}, subscribers: map[uint][]chan Event{}}
+
+//
+// Default returns the process-wide Manager.
+func Default() *Manager {
+	return singleton
+}
+
+//
+// Start launches a background worker for the given summary and returns
+// immediately. The caller is expected to have already persisted the
+// ImportSummary and its uploaded content.
+func (m *Manager) Start(db *gorm.DB, summaryID uint, fileName string, total int, rows <-chan model.Import) {
+	m.mutex.Lock()
+	j := &job{
+		progress: Progress{SummaryID: summaryID, Total: total, Status: StatusRunning},
+		cancel:   make(chan struct{}),
+	}
+	m.jobs[summaryID] = j
+	m.mutex.Unlock()
+
+	w := Worker{
+		db:        db,
+		summaryID: summaryID,
+		fileName:  fileName,
+		total:     total,
+		cancel:    j.cancel,
+		report:    func(p Progress) { m.update(summaryID, p) },
+		publish:   func(e Event) { m.publish(summaryID, e) },
+	}
+	go func() {
+		_ = w.Run(rows)
+	}()
+}
+
+//
+// update records the latest progress for a job, scheduling its eviction
+// once that progress reaches a terminal status so a long-lived process
+// doesn't accumulate one entry per import forever.
+func (m *Manager) update(summaryID uint, p Progress) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if j, found := m.jobs[summaryID]; found {
+		j.progress = p
+	}
+	if isTerminal(p.Status) {
+		time.AfterFunc(jobRetention, func() { m.evict(summaryID) })
+	}
+}
+
+//
+// evict removes a job's in-memory progress once it's no longer needed.
+func (m *Manager) evict(summaryID uint) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.jobs, summaryID)
+}
+
+//
+// Progress returns the last known progress for a summary.
+func (m *Manager) Progress(summaryID uint) (p Progress, found bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	j, found := m.jobs[summaryID]
+	if !found {
+		return
+	}
+	p = j.progress
+	return
+}
+
+//
+// Cancel requests that a running import stop processing further rows.
+// Rows already created are left in place; the summary is marked Canceled.
+func (m *Manager) Cancel(summaryID uint) (found bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	j, found := m.jobs[summaryID]
+	if !found {
+		return
+	}
+	select {
+	case <-j.cancel:
+		// already canceled.
+	default:
+		close(j.cancel)
+	}
+	return
+}
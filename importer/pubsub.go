@@ -0,0 +1,67 @@
+package importer
+
+//
+// EventType names the kind of notification published as a job advances.
+type EventType string
+
+const (
+	EventRowProcessed EventType = "row_processed"
+	EventRowFailed    EventType = "row_failed"
+	EventCompleted    EventType = "completed"
+	EventCanceled     EventType = "canceled"
+	EventFailed       EventType = "failed"
+)
+
+//
+// Event is a single notification pushed to subscribers of a running
+// import, consumed by the api package's SSE handler.
+type Event struct {
+	Type      EventType `json:"type"`
+	Processed int       `json:"processed"`
+	Total     int       `json:"total"`
+	Error     string    `json:"error,omitempty"`
+}
+
+//
+// Subscribe registers for events on the given summary and returns a
+// channel of them along with an unsubscribe func the caller must call
+// when done listening.
+func (m *Manager) Subscribe(summaryID uint) (ch chan Event, unsubscribe func()) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	ch = make(chan Event, 16)
+	m.subscribers[summaryID] = append(m.subscribers[summaryID], ch)
+	unsubscribe = func() {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+		subs := m.subscribers[summaryID]
+		for i, s := range subs {
+			if s == ch {
+				subs = append(subs[:i], subs[i+1:]...)
+				break
+			}
+		}
+		if len(subs) == 0 {
+			delete(m.subscribers, summaryID)
+		} else {
+			m.subscribers[summaryID] = subs
+		}
+		close(ch)
+	}
+	return
+}
+
+//
+// publish delivers an event to every current subscriber of a summary,
+// dropping it for any subscriber whose buffer is full rather than
+// blocking the importer on a slow client.
+func (m *Manager) publish(summaryID uint, e Event) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for _, ch := range m.subscribers[summaryID] {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
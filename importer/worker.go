@@ -0,0 +1,278 @@
+package importer
+
+import (
+	"github.com/konveyor/tackle2-hub/model"
+	"gorm.io/gorm"
+)
+
+//
+// Record types: the model.Import.RecordType1 values identifying an
+// application row and a dependency row, shared with the api package's row
+// parsers.
+const (
+	RecordTypeApplication = "1"
+	RecordTypeDependency  = "2"
+)
+
+//
+// Worker consumes a stream of parsed model.Import rows, resolving
+// referenced business services and tags and creating the real
+// Application/Dependency/Tag records, while reporting progress back to
+// its Manager. It is parser-agnostic: the rows may have come from CSV,
+// XLSX or JSON.
+type Worker struct {
+	db        *gorm.DB
+	summaryID uint
+	fileName  string
+	total     int
+	cancel    chan struct{}
+	report    func(Progress)
+	publish   func(Event)
+}
+
+//
+// Run drains rows until the channel closes, cancellation or a fatal error.
+func (w *Worker) Run(rows <-chan model.Import) (err error) {
+	seen := map[string]bool{}
+	processed := 0
+	for {
+		select {
+		case <-w.cancel:
+			drain(rows)
+			w.finish(StatusCanceled, "")
+			w.notify(Event{Type: EventCanceled, Total: w.total, Processed: processed})
+			return
+		case imp, open := <-rows:
+			if !open {
+				w.report(Progress{SummaryID: w.summaryID, Total: w.total, Processed: processed, Status: StatusCompleted})
+				w.persistStatus(StatusCompleted)
+				w.notify(Event{Type: EventCompleted, Total: w.total, Processed: processed})
+				return
+			}
+			imp.ImportSummaryID = w.summaryID
+			imp.Filename = w.fileName
+			w.process(&imp, seen, false)
+			result := w.db.Create(&imp)
+			if result.Error != nil {
+				err = result.Error
+				drain(rows)
+				w.finish(StatusFailed, err.Error())
+				w.notify(Event{Type: EventFailed, Total: w.total, Processed: processed, Error: err.Error()})
+				return
+			}
+			processed++
+			w.report(Progress{
+				SummaryID: w.summaryID,
+				Total:     w.total,
+				Processed: processed,
+				Status:    StatusRunning,
+			})
+			if imp.IsValid {
+				w.notify(Event{Type: EventRowProcessed, Total: w.total, Processed: processed})
+			} else {
+				w.notify(Event{Type: EventRowFailed, Total: w.total, Processed: processed, Error: imp.ErrorMessage})
+			}
+		}
+	}
+}
+
+//
+// drain discards every row still in flight on a channel Run is about to
+// stop reading from. Every parser feeds rows from a goroutine sending on
+// an unbuffered channel, so leaving early without this would leave that
+// goroutine blocked forever on its next send.
+func drain(rows <-chan model.Import) {
+	for range rows {
+	}
+}
+
+//
+// finish marks the job done and reports its terminal status.
+func (w *Worker) finish(status string, errMsg string) {
+	w.report(Progress{SummaryID: w.summaryID, Total: w.total, Status: status, Error: errMsg})
+	w.persistStatus(status)
+}
+
+//
+// persistStatus writes the job's terminal status onto the ImportSummary
+// row itself, so GetSummary/ListSummaries reflect it correctly even
+// though every model.Import row that exists is, by construction,
+// already Processed -- row counts alone can no longer signal completion.
+func (w *Worker) persistStatus(status string) {
+	w.db.Model(&model.ImportSummary{}).Where("id = ?", w.summaryID).Update("importstatus", status)
+}
+
+//
+// notify publishes an event if a subscriber-notifying Manager wired one
+// in; Validate's ad-hoc Worker leaves this nil since dry runs have no
+// subscribers.
+func (w *Worker) notify(e Event) {
+	if w.publish != nil {
+		w.publish(e)
+	}
+}
+
+//
+// Validate runs the same validation a real import would, without
+// creating any Application/Dependency/Tag records, and returns every row
+// with its Processed/IsValid/ErrorMessage set. Used by the Upload
+// handler's dry-run mode.
+func Validate(db *gorm.DB, rows <-chan model.Import) (results []model.Import) {
+	w := &Worker{db: db}
+	seen := map[string]bool{}
+	for imp := range rows {
+		w.process(&imp, seen, true)
+		results = append(results, imp)
+	}
+	return
+}
+
+//
+// process validates an import row against existing reference data and, if
+// valid and not a dry run, creates the Application/Dependency/Tag it
+// describes. The row's Processed/IsValid/ErrorMessage fields are set to
+// reflect the outcome. seen tracks application names already encountered
+// in this batch so duplicates are reported instead of silently colliding.
+func (w *Worker) process(imp *model.Import, seen map[string]bool, dryRun bool) {
+	imp.Processed = true
+	if imp.ErrorMessage != "" {
+		imp.IsValid = false
+		return
+	}
+	switch imp.RecordType1 {
+	case RecordTypeDependency:
+		w.processDependency(imp, seen, dryRun)
+	default:
+		w.processApplication(imp, seen, dryRun)
+	}
+}
+
+//
+// processApplication resolves the business service and tags referenced by
+// an application row and, unless dryRun, creates the Application record.
+func (w *Worker) processApplication(imp *model.Import, seen map[string]bool, dryRun bool) {
+	if imp.ApplicationName == "" {
+		imp.IsValid = false
+		imp.ErrorMessage = "Application name is required."
+		return
+	}
+	if seen[imp.ApplicationName] {
+		imp.IsValid = false
+		imp.ErrorMessage = "Duplicate application name in import: " + imp.ApplicationName
+		return
+	}
+	app := &model.Application{
+		Name:        imp.ApplicationName,
+		Description: imp.Description,
+		Comments:    imp.Comments,
+	}
+	if imp.BusinessService != "" {
+		bs := &model.BusinessService{}
+		result := w.db.Where("name = ?", imp.BusinessService).First(bs)
+		if result.Error != nil {
+			imp.IsValid = false
+			imp.ErrorMessage = "Business service not found: " + imp.BusinessService
+			return
+		}
+		app.BusinessServiceID = &bs.ID
+	}
+	for _, t := range imp.ImportTags {
+		tagType := &model.TagType{}
+		result := w.db.Where("name = ?", t.TagType).First(tagType)
+		if result.Error != nil {
+			imp.IsValid = false
+			imp.ErrorMessage = "Tag type not found: " + t.TagType
+			return
+		}
+		tag := &model.Tag{}
+		result = w.db.Where("name = ? AND tag_type_id = ?", t.Name, tagType.ID).First(tag)
+		if result.Error != nil {
+			imp.IsValid = false
+			imp.ErrorMessage = "Tag not found: " + t.Name
+			return
+		}
+		app.Tags = append(app.Tags, *tag)
+	}
+	if !dryRun {
+		result := w.db.Create(app)
+		if result.Error != nil {
+			imp.IsValid = false
+			imp.ErrorMessage = result.Error.Error()
+			return
+		}
+	}
+	seen[imp.ApplicationName] = true
+	if imp.Dependency != "" {
+		w.createDependency(imp, app.Name, seen, dryRun)
+	}
+	imp.IsValid = imp.ErrorMessage == ""
+}
+
+//
+// processDependency resolves both sides of a dependency row and, unless
+// dryRun, creates the Dependency record.
+func (w *Worker) processDependency(imp *model.Import, seen map[string]bool, dryRun bool) {
+	if imp.ApplicationName == "" || imp.Dependency == "" {
+		imp.IsValid = false
+		imp.ErrorMessage = "Application and dependency names are required."
+		return
+	}
+	w.createDependency(imp, imp.ApplicationName, seen, dryRun)
+	if imp.ErrorMessage == "" {
+		imp.IsValid = true
+	}
+}
+
+//
+// createDependency looks up both applications by name and, unless
+// dryRun, creates the Dependency record oriented by DependencyDirection.
+func (w *Worker) createDependency(imp *model.Import, appName string, seen map[string]bool, dryRun bool) {
+	app, err := w.resolveApplication(appName, seen)
+	if err != nil {
+		imp.IsValid = false
+		imp.ErrorMessage = "Application not found: " + appName
+		return
+	}
+	other, err := w.resolveApplication(imp.Dependency, seen)
+	if err != nil {
+		imp.IsValid = false
+		imp.ErrorMessage = "Dependency application not found: " + imp.Dependency
+		return
+	}
+	if dryRun {
+		return
+	}
+	dep := &model.Dependency{}
+	if imp.DependencyDirection == "northbound" || imp.DependencyDirection == "NORTHBOUND" {
+		dep.ToID = app.ID
+		dep.FromID = other.ID
+	} else {
+		dep.FromID = app.ID
+		dep.ToID = other.ID
+	}
+	result := w.db.Create(dep)
+	if result.Error != nil {
+		imp.IsValid = false
+		imp.ErrorMessage = result.Error.Error()
+	}
+}
+
+//
+// resolveApplication looks up an application by name, falling back to
+// the in-batch seen set for names already validated earlier in the same
+// dry run but, since dry runs never persist, not actually found in the
+// DB yet. A real (non-dry) run always finds the row in the DB instead,
+// since processApplication creates each row before moving to the next.
+func (w *Worker) resolveApplication(name string, seen map[string]bool) (app *model.Application, err error) {
+	app = &model.Application{}
+	result := w.db.Where("name = ?", name).First(app)
+	if result.Error == nil {
+		return
+	}
+	if seen[name] {
+		app.Name = name
+		return
+	}
+	err = result.Error
+	return
+}
@@ -0,0 +1,72 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+//
+// setupDB returns an in-memory DB migrated with just the models this
+// file's tests need to resolve applications against.
+func setupDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.AutoMigrate(&model.Application{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+//
+// Validate must resolve a dependency referencing an application created
+// by an earlier row in the same batch, since dry runs never persist
+// rows, so a pure DB lookup would otherwise report it as not found even
+// though a real (non-dry) upload would have already committed it.
+func TestValidateResolvesPriorRowInSameBatch(t *testing.T) {
+	db := setupDB(t)
+	rows := make(chan model.Import, 2)
+	rows <- model.Import{RecordType1: RecordTypeApplication, ApplicationName: "MyApplication"}
+	rows <- model.Import{
+		RecordType1:         RecordTypeApplication,
+		ApplicationName:     "OtherApplication",
+		Dependency:          "MyApplication",
+		DependencyDirection: "southbound",
+	}
+	close(rows)
+
+	results := Validate(db, rows)
+	if !results[0].IsValid {
+		t.Fatalf("expected MyApplication row to be valid, got error: %s", results[0].ErrorMessage)
+	}
+	if !results[1].IsValid {
+		t.Fatalf("expected dependency on MyApplication to resolve via the batch, got error: %s", results[1].ErrorMessage)
+	}
+}
+
+//
+// process must reject a second row naming an application already seen
+// earlier in the same batch.
+func TestProcessApplicationRejectsDuplicateName(t *testing.T) {
+	db := setupDB(t)
+	w := &Worker{db: db}
+	seen := map[string]bool{}
+
+	first := &model.Import{RecordType1: RecordTypeApplication, ApplicationName: "MyApplication"}
+	w.process(first, seen, true)
+	if !first.IsValid {
+		t.Fatalf("expected first occurrence to be valid, got error: %s", first.ErrorMessage)
+	}
+
+	second := &model.Import{RecordType1: RecordTypeApplication, ApplicationName: "MyApplication"}
+	w.process(second, seen, true)
+	if second.IsValid {
+		t.Fatal("expected duplicate application name to be rejected")
+	}
+}